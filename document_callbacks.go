@@ -0,0 +1,167 @@
+package mongoid
+
+/*
+This file implements the optional lifecycle callback hooks that a document/model can
+implement on its concrete type, plus the Timestamps helper that uses those hooks to
+maintain CreatedAt/UpdatedAt automatically. Mirrors Mongoid's before/after save/create/
+update/destroy callbacks.
+*/
+
+import (
+	"mongoid/log"
+	"reflect"
+	"time"
+)
+
+// BeforeSave is implemented by documents that want to run logic immediately before
+// either an insert or an update. Returning an error aborts the Save().
+type BeforeSave interface {
+	BeforeSave() error
+}
+
+// AfterSave is implemented by documents that want to run logic immediately after
+// either an insert or an update has succeeded.
+type AfterSave interface {
+	AfterSave() error
+}
+
+// BeforeCreate is implemented by documents that want to run logic immediately before
+// the document's first insert. Returning an error aborts the Save().
+type BeforeCreate interface {
+	BeforeCreate() error
+}
+
+// AfterCreate is implemented by documents that want to run logic immediately after
+// the document's first insert has succeeded.
+type AfterCreate interface {
+	AfterCreate() error
+}
+
+// BeforeUpdate is implemented by documents that want to run logic immediately before
+// an already-persisted document is updated. Returning an error aborts the Save().
+type BeforeUpdate interface {
+	BeforeUpdate() error
+}
+
+// AfterUpdate is implemented by documents that want to run logic immediately after
+// an already-persisted document has been updated.
+type AfterUpdate interface {
+	AfterUpdate() error
+}
+
+// BeforeDestroy is implemented by documents that want to run logic immediately before
+// the document is removed from the database. Returning an error aborts the Destroy().
+type BeforeDestroy interface {
+	BeforeDestroy() error
+}
+
+// AfterDestroy is implemented by documents that want to run logic immediately after
+// the document has been removed from the database.
+type AfterDestroy interface {
+	AfterDestroy() error
+}
+
+// runBeforeSaveCallbacks dispatches BeforeCreate/BeforeUpdate followed by BeforeSave on
+// the document's concrete type, if implemented.
+func (d *Base) runBeforeSaveCallbacks(isNewRecord bool) error {
+	return runBeforeSaveCallbacksOn(d.self, isNewRecord)
+}
+
+// runAfterSaveCallbacks dispatches AfterCreate/AfterUpdate followed by AfterSave on the
+// document's concrete type, if implemented.
+func (d *Base) runAfterSaveCallbacks(isNewRecord bool) error {
+	return runAfterSaveCallbacksOn(d.self, isNewRecord)
+}
+
+// runBeforeSaveCallbacksOn dispatches BeforeCreate/BeforeUpdate followed by BeforeSave
+// on doc's concrete type, if implemented. Shared by Base.Save()/SaveCtx() and
+// UnitOfWork.Flush(), which both need to run these hooks on a document they don't own.
+func runBeforeSaveCallbacksOn(doc interface{}, isNewRecord bool) error {
+	if isNewRecord {
+		if hook, ok := doc.(BeforeCreate); ok {
+			if err := hook.BeforeCreate(); err != nil {
+				return err
+			}
+		}
+	} else {
+		if hook, ok := doc.(BeforeUpdate); ok {
+			if err := hook.BeforeUpdate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if hook, ok := doc.(BeforeSave); ok {
+		return hook.BeforeSave()
+	}
+	return nil
+}
+
+// runAfterSaveCallbacksOn dispatches AfterCreate/AfterUpdate followed by AfterSave on
+// doc's concrete type, if implemented. See runBeforeSaveCallbacksOn.
+func runAfterSaveCallbacksOn(doc interface{}, isNewRecord bool) error {
+	if isNewRecord {
+		if hook, ok := doc.(AfterCreate); ok {
+			if err := hook.AfterCreate(); err != nil {
+				return err
+			}
+		}
+	} else {
+		if hook, ok := doc.(AfterUpdate); ok {
+			if err := hook.AfterUpdate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if hook, ok := doc.(AfterSave); ok {
+		return hook.AfterSave()
+	}
+	return nil
+}
+
+// Timestamps is an embeddable struct that gives a model automatic CreatedAt/UpdatedAt
+// (UTC) tracking, mirroring mgm's DateFields. Embed it alongside Base:
+//
+//	type User struct {
+//	    mongoid.Base
+//	    mongoid.Timestamps
+//	    Name string
+//	}
+//
+// saveByInsert/saveByUpdate detect the embedded Timestamps field via reflection and
+// stamp it directly -- deliberately not through the BeforeCreate/BeforeSave hook
+// interfaces, since a model that implements its own BeforeSave()/BeforeCreate() would
+// otherwise shadow Timestamps' promoted methods of the same name and silently stop
+// getting timestamps.
+type Timestamps struct {
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// touchTimestamps finds a Timestamps field embedded (directly or promoted through
+// further embedding) in doc's concrete struct and stamps it with the current UTC time,
+// populating CreatedAt only on a new record. It is a no-op if doc has no such field.
+func touchTimestamps(doc interface{}, isNewRecord bool) {
+	value := reflect.ValueOf(doc)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	field := value.FieldByName("Timestamps")
+	if !field.IsValid() || !field.CanAddr() || field.Type() != reflect.TypeOf(Timestamps{}) {
+		return
+	}
+
+	log.Debug("touchTimestamps()")
+	timestamps := field.Addr().Interface().(*Timestamps)
+
+	now := time.Now().UTC()
+	if isNewRecord {
+		timestamps.CreatedAt = now
+	}
+	timestamps.UpdatedAt = now
+}
@@ -0,0 +1,129 @@
+package mongoid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type callbackRecorder struct {
+	calls []string
+	errOn string
+}
+
+func (c *callbackRecorder) record(name string) error {
+	c.calls = append(c.calls, name)
+	if c.errOn == name {
+		return errors.New(name + " failed")
+	}
+	return nil
+}
+
+func (c *callbackRecorder) BeforeCreate() error { return c.record("BeforeCreate") }
+func (c *callbackRecorder) AfterCreate() error  { return c.record("AfterCreate") }
+func (c *callbackRecorder) BeforeUpdate() error { return c.record("BeforeUpdate") }
+func (c *callbackRecorder) AfterUpdate() error  { return c.record("AfterUpdate") }
+func (c *callbackRecorder) BeforeSave() error   { return c.record("BeforeSave") }
+func (c *callbackRecorder) AfterSave() error    { return c.record("AfterSave") }
+
+func TestRunBeforeSaveCallbacksOnOrdering(t *testing.T) {
+	cases := []struct {
+		name        string
+		isNewRecord bool
+		want        []string
+	}{
+		{name: "new record runs BeforeCreate then BeforeSave", isNewRecord: true, want: []string{"BeforeCreate", "BeforeSave"}},
+		{name: "persisted record runs BeforeUpdate then BeforeSave", isNewRecord: false, want: []string{"BeforeUpdate", "BeforeSave"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := &callbackRecorder{}
+			if err := runBeforeSaveCallbacksOn(doc, tc.isNewRecord); err != nil {
+				t.Fatalf("runBeforeSaveCallbacksOn() returned %v, want nil", err)
+			}
+			if len(doc.calls) != len(tc.want) {
+				t.Fatalf("calls = %v, want %v", doc.calls, tc.want)
+			}
+			for i, name := range tc.want {
+				if doc.calls[i] != name {
+					t.Errorf("calls[%d] = %q, want %q", i, doc.calls[i], name)
+				}
+			}
+		})
+	}
+}
+
+func TestRunBeforeSaveCallbacksOnStopsAfterCreateError(t *testing.T) {
+	doc := &callbackRecorder{errOn: "BeforeCreate"}
+	if err := runBeforeSaveCallbacksOn(doc, true); err == nil {
+		t.Fatal("runBeforeSaveCallbacksOn() = nil, want the BeforeCreate error")
+	}
+	if len(doc.calls) != 1 {
+		t.Errorf("calls = %v, want BeforeSave to be skipped once BeforeCreate fails", doc.calls)
+	}
+}
+
+func TestRunAfterSaveCallbacksOnOrdering(t *testing.T) {
+	doc := &callbackRecorder{}
+	if err := runAfterSaveCallbacksOn(doc, true); err != nil {
+		t.Fatalf("runAfterSaveCallbacksOn() returned %v, want nil", err)
+	}
+	want := []string{"AfterCreate", "AfterSave"}
+	if len(doc.calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", doc.calls, want)
+	}
+	for i, name := range want {
+		if doc.calls[i] != name {
+			t.Errorf("calls[%d] = %q, want %q", i, doc.calls[i], name)
+		}
+	}
+}
+
+func TestRunBeforeSaveCallbacksOnDocWithoutHooks(t *testing.T) {
+	if err := runBeforeSaveCallbacksOn(struct{}{}, true); err != nil {
+		t.Errorf("runBeforeSaveCallbacksOn(no hooks) = %v, want nil", err)
+	}
+	if err := runAfterSaveCallbacksOn(struct{}{}, false); err != nil {
+		t.Errorf("runAfterSaveCallbacksOn(no hooks) = %v, want nil", err)
+	}
+}
+
+type withTimestamps struct {
+	Timestamps
+	Name string
+}
+
+func TestTouchTimestampsSetsCreatedAtOnlyForNewRecords(t *testing.T) {
+	doc := &withTimestamps{}
+
+	touchTimestamps(doc, true)
+	if doc.CreatedAt.IsZero() {
+		t.Error("touchTimestamps(isNewRecord=true) left CreatedAt zero")
+	}
+	if doc.UpdatedAt.IsZero() {
+		t.Error("touchTimestamps(isNewRecord=true) left UpdatedAt zero")
+	}
+
+	createdAt := doc.CreatedAt
+	time.Sleep(time.Millisecond)
+	touchTimestamps(doc, false)
+
+	if !doc.CreatedAt.Equal(createdAt) {
+		t.Errorf("touchTimestamps(isNewRecord=false) changed CreatedAt from %v to %v", createdAt, doc.CreatedAt)
+	}
+	if !doc.UpdatedAt.After(createdAt) {
+		t.Errorf("touchTimestamps(isNewRecord=false) left UpdatedAt %v, want it after %v", doc.UpdatedAt, createdAt)
+	}
+}
+
+func TestTouchTimestampsNoOpWithoutEmbeddedTimestamps(t *testing.T) {
+	type noTimestamps struct {
+		Name string
+	}
+	doc := &noTimestamps{Name: "unchanged"}
+	touchTimestamps(doc, true) // must not panic, must not alter the struct
+	if doc.Name != "unchanged" {
+		t.Errorf("touchTimestamps mutated a struct with no Timestamps field: %v", doc)
+	}
+}
@@ -0,0 +1,98 @@
+package mongoid
+
+/*
+This file implements the dirty-tracking inspection methods (Was, ChangedFields,
+WillSave) and Reload(), rounding out the change-tracking API that Changes() only
+partially covers.
+*/
+
+import (
+	"context"
+	"mongoid/log"
+	"reflect"
+	"strings"
+)
+
+// bsonValueAtPath walks doc via a dotted path (e.g. "address.city") and returns the
+// value found there, or (nil, false) if any segment of the path is missing.
+func bsonValueAtPath(doc BsonDocument, path string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		asDoc, ok := current.(BsonDocument)
+		if !ok {
+			return nil, false
+		}
+		value, found := asDoc[part]
+		if !found {
+			return nil, false
+		}
+		current = value
+	}
+	return current, true
+}
+
+// Was provides the previous value at fieldPath -- a dotted path into nested
+// sub-documents is supported, e.g. "address.city" -- and reports whether that value
+// differs from the document's current value.
+func (d *Base) Was(fieldPath string) (interface{}, bool) {
+	log.Debug("Base.Was(", fieldPath, ")")
+
+	previousValue, previousFound := bsonValueAtPath(d.previousValue, fieldPath)
+	currentValue, currentFound := bsonValueAtPath(d.ToBson(), fieldPath)
+
+	changed := previousFound != currentFound || !reflect.DeepEqual(previousValue, currentValue)
+	return previousValue, changed
+}
+
+// ChangedFields returns the top-level field names that differ between the document's
+// current values and its last-persisted (or last-loaded) values.
+func (d *Base) ChangedFields() []string {
+	log.Debug("Base.ChangedFields()")
+	diffBson := d.Changes()
+	fields := make([]string, 0, len(diffBson))
+	for key := range diffBson {
+		fields = append(fields, key)
+	}
+	return fields
+}
+
+// WillSave reports whether the given top-level field has a pending change that the
+// next Save() would write.
+func (d *Base) WillSave(field string) bool {
+	log.Debug("Base.WillSave(", field, ")")
+	_, changed := d.Was(field)
+	return changed
+}
+
+// Reload re-fetches the document from the database by its _id, replacing both the
+// current field values and the change-tracking snapshot with what is now persisted.
+func (d *Base) Reload(ctx context.Context) error {
+	log.Debug("Base.Reload()")
+
+	if !d.IsPersisted() {
+		log.Panic("cannot Reload() a document that has not been persisted")
+	}
+
+	if txn := transactionStateFromContext(ctx); txn != nil {
+		txn.track(d)
+	}
+
+	collection := d.getMongoDriverCollectionRef()
+	filterBson := BsonDocument{"_id": d.ToBson()["_id"]}
+
+	var resultBson BsonDocument
+	if err := collection.FindOne(ctx, filterBson).Decode(&resultBson); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	for key, value := range resultBson {
+		if err := d.SetField(key, value); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	d.refreshPreviousValueBSON() // update change tracking with current values
+	return nil
+}
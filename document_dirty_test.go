@@ -0,0 +1,34 @@
+package mongoid
+
+import "testing"
+
+func TestBsonValueAtPath(t *testing.T) {
+	doc := BsonDocument{
+		"name": "Ada",
+		"address": BsonDocument{
+			"city": "London",
+		},
+	}
+
+	cases := []struct {
+		name      string
+		path      string
+		wantValue interface{}
+		wantFound bool
+	}{
+		{name: "top-level hit", path: "name", wantValue: "Ada", wantFound: true},
+		{name: "nested hit", path: "address.city", wantValue: "London", wantFound: true},
+		{name: "missing top-level key", path: "missing", wantValue: nil, wantFound: false},
+		{name: "missing nested key", path: "address.country", wantValue: nil, wantFound: false},
+		{name: "path descends through a non-document value", path: "name.first", wantValue: nil, wantFound: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, found := bsonValueAtPath(doc, tc.path)
+			if found != tc.wantFound || value != tc.wantValue {
+				t.Errorf("bsonValueAtPath(doc, %q) = (%v, %v), want (%v, %v)", tc.path, value, found, tc.wantValue, tc.wantFound)
+			}
+		})
+	}
+}
@@ -43,39 +43,145 @@ func (d *Base) Changes() BsonDocument {
 	return diffBson
 }
 
-// Was provides the previous field value and indicates if a change has occurred
-func (d *Base) Was(fieldPath string) (interface{}, bool) {
-	log.Panicf("NYI -Base.Was_(%s)", fieldPath)
-	return nil, false
-}
-
 // Save will store the changed attributes to the database atomically, or insert the document if flagged as a new record via Model#new_record?
 // Can bypass validations if wanted.
 func (d *Base) Save() error {
 	log.Debug("Base.Save()")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return d.SaveCtx(ctx)
+}
+
+// SaveCtx behaves like Save(), but threads ctx into the driver calls. Pass a
+// mongo.SessionContext (see Client.WithTransaction) to have the write join an
+// in-progress transaction.
+func (d *Base) SaveCtx(ctx context.Context) error {
+	log.Debug("Base.SaveCtx()")
+
+	if txn := transactionStateFromContext(ctx); txn != nil {
+		txn.track(d)
+	}
+
+	isNewRecord := !d.IsPersisted()
+
+	// stamp any embedded Timestamps field before user hooks run, so it can't be
+	// shadowed by a model's own BeforeCreate()/BeforeSave() of the same name
+	touchTimestamps(d.self, isNewRecord)
+
+	if err := d.runBeforeSaveCallbacks(isNewRecord); err != nil {
+		return err
+	}
 
 	// if already persisted, this is an update, otherwise it's a new insert
-	if d.IsPersisted() {
-		// update goes here
-		return d.saveByUpdate()
+	var err error
+	if isNewRecord {
+		err = d.saveByInsert(ctx)
+	} else {
+		err = d.saveByUpdate(ctx)
 	}
-	return d.saveByInsert()
+	if err != nil {
+		return err
+	}
+
+	return d.runAfterSaveCallbacks(isNewRecord)
 }
 
-func (d *Base) saveByUpdate() error {
+// Destroy removes the document from the database, invoking BeforeDestroy/AfterDestroy callbacks if present.
+// Is a no-op if the document has not been persisted.
+func (d *Base) Destroy() error {
+	log.Debug("Base.Destroy()")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return d.DestroyCtx(ctx)
+}
+
+// DestroyCtx behaves like Destroy(), but threads ctx into the driver call. Pass a
+// mongo.SessionContext (see Client.WithTransaction) to have the delete join an
+// in-progress transaction.
+func (d *Base) DestroyCtx(ctx context.Context) error {
+	log.Debug("Base.DestroyCtx()")
+
+	if !d.IsPersisted() {
+		return nil
+	}
+
+	if txn := transactionStateFromContext(ctx); txn != nil {
+		txn.track(d)
+	}
+
+	if hook, ok := d.self.(BeforeDestroy); ok {
+		if err := hook.BeforeDestroy(); err != nil {
+			return err
+		}
+	}
+
+	collection := d.getMongoDriverCollectionRef()
+	filterBson := BsonDocument{"_id": d.ToBson()["_id"]}
+
+	if _, err := collection.DeleteOne(ctx, filterBson); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	d.persisted = false
+
+	if hook, ok := d.self.(AfterDestroy); ok {
+		return hook.AfterDestroy()
+	}
+	return nil
+}
+
+// buildSetUnsetUpdate turns a Changes()-style diff BsonDocument into a MongoDB update
+// document, routing nil-valued (unset) keys into "$unset" and the rest into "$set".
+func buildSetUnsetUpdate(diffBson BsonDocument) BsonDocument {
+	setBson := BsonDocument{}
+	unsetBson := BsonDocument{}
+	for key, value := range diffBson {
+		if value == nil {
+			unsetBson[key] = ""
+		} else {
+			setBson[key] = value
+		}
+	}
+
+	updateBson := BsonDocument{}
+	if len(setBson) > 0 {
+		updateBson["$set"] = setBson
+	}
+	if len(unsetBson) > 0 {
+		updateBson["$unset"] = unsetBson
+	}
+	return updateBson
+}
+
+func (d *Base) saveByUpdate(ctx context.Context) error {
 	log.Debug("saveByUpdate()")
-	// insert a new object
-	log.Fatal("NYI Save() - PERSISTED")
+
+	diffBson := d.Changes()
+	if len(diffBson) == 0 {
+		// nothing changed since the last load, nothing to write
+		return nil
+	}
+
+	updateBson := buildSetUnsetUpdate(diffBson)
+	collection := d.getMongoDriverCollectionRef()
+	filterBson := BsonDocument{"_id": d.ToBson()["_id"]}
+
+	if _, err := collection.UpdateOne(ctx, filterBson, updateBson); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	d.refreshPreviousValueBSON() // update change tracking with current values
 	return nil
 }
 
-func (d *Base) saveByInsert() error {
+func (d *Base) saveByInsert(ctx context.Context) error {
 	log.Debug("saveByInsert()")
 	// insert a new object
 
 	// TODO: TEMP FIX ME
 	collection := d.getMongoDriverCollectionRef()
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
 
 	insertBson := d.ToBson()
 	// log.Error("insertBson: ", insertBson)
@@ -93,7 +199,8 @@ func (d *Base) saveByInsert() error {
 
 	res, err := collection.InsertOne(ctx, insertBson)
 	if err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		return wrapWriteError(err)
 	}
 
 	id := res.InsertedID
@@ -0,0 +1,47 @@
+package mongoid
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildSetUnsetUpdate(t *testing.T) {
+	cases := []struct {
+		name     string
+		diff     BsonDocument
+		expected BsonDocument
+	}{
+		{
+			name:     "empty diff produces an empty update",
+			diff:     BsonDocument{},
+			expected: BsonDocument{},
+		},
+		{
+			name: "nil values go into $unset, non-nil values into $set",
+			diff: BsonDocument{"name": "Ada", "nickname": nil},
+			expected: BsonDocument{
+				"$set":   BsonDocument{"name": "Ada"},
+				"$unset": BsonDocument{"nickname": ""},
+			},
+		},
+		{
+			name:     "an all-nil diff only produces $unset",
+			diff:     BsonDocument{"nickname": nil},
+			expected: BsonDocument{"$unset": BsonDocument{"nickname": ""}},
+		},
+		{
+			name:     "an all-set diff only produces $set",
+			diff:     BsonDocument{"name": "Ada"},
+			expected: BsonDocument{"$set": BsonDocument{"name": "Ada"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := buildSetUnsetUpdate(tc.diff)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("buildSetUnsetUpdate(%v) = %v, want %v", tc.diff, actual, tc.expected)
+			}
+		})
+	}
+}
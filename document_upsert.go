@@ -0,0 +1,106 @@
+package mongoid
+
+/*
+This file implements Upsert() on Base and the model-level FindOrCreateBy() helper, both
+of which let callers avoid the race between a Find and a subsequent Insert by pushing
+the whole read-modify-write down into a single atomic MongoDB operation.
+*/
+
+import (
+	"context"
+	"mongoid/log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Upsert stores the changed attributes to the database, inserting a new document that
+// matches filterBson if none exists yet. Unlike Save(), this issues a single atomic
+// UpdateOne with Upsert:true regardless of IsPersisted(), so it is safe to call even on
+// documents that were never Find()'d in this process.
+func (d *Base) Upsert(filterBson BsonDocument) error {
+	log.Debug("Base.Upsert()")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return d.UpsertCtx(ctx, filterBson)
+}
+
+// UpsertCtx behaves like Upsert(), but threads ctx into the driver call. Pass a
+// mongo.SessionContext (see Client.WithTransaction) to have the write join an
+// in-progress transaction.
+func (d *Base) UpsertCtx(ctx context.Context, filterBson BsonDocument) error {
+	log.Debug("Base.UpsertCtx()")
+
+	if txn := transactionStateFromContext(ctx); txn != nil {
+		txn.track(d)
+	}
+
+	updateBson := buildSetUnsetUpdate(d.Changes())
+	collection := d.getMongoDriverCollectionRef()
+	opts := options.Update().SetUpsert(true)
+
+	res, err := collection.UpdateOne(ctx, filterBson, updateBson, opts)
+	if err != nil {
+		return wrapWriteError(err)
+	}
+
+	if res.UpsertedID != nil {
+		if err := d.SetField("_id", res.UpsertedID); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	d.persisted = true
+	d.refreshPreviousValueBSON() // update change tracking with current values
+	return nil
+}
+
+// FindOrCreateBy atomically finds the document matching filterBson, or inserts one
+// built from filterBson merged with defaultsBson if none exists, returning the
+// resulting document either way. Uses FindOneAndUpdate with Upsert:true so concurrent
+// callers never race a Find() against an Insert() for the same filter.
+func (m *Model) FindOrCreateBy(filterBson BsonDocument, defaultsBson BsonDocument) (IDocumentBase, error) {
+	log.Debug("Model.FindOrCreateBy()")
+
+	client := m.GetClient()
+	collection := client.getMongoCollectionHandle(m.GetDatabaseName(), m.GetCollectionName())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// seed with the filter fields too so the inserted document has at least one field to
+	// $setOnInsert whenever the filter or the defaults are non-empty -- MongoDB rejects an
+	// explicit "$setOnInsert" with no fields in it, so with both empty the update is a bare "{}"
+	setOnInsertBson := BsonDocument{}
+	for key, value := range filterBson {
+		setOnInsertBson[key] = value
+	}
+	for key, value := range defaultsBson {
+		setOnInsertBson[key] = value
+	}
+	updateBson := BsonDocument{}
+	if len(setOnInsertBson) > 0 {
+		updateBson["$setOnInsert"] = setOnInsertBson
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var resultBson BsonDocument
+	if err := collection.FindOneAndUpdate(ctx, filterBson, updateBson, opts).Decode(&resultBson); err != nil {
+		return nil, wrapWriteError(err)
+	}
+
+	doc := m.New()
+	for key, value := range resultBson {
+		if err := doc.SetField(key, value); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	if flushableDoc, ok := doc.(flushable); ok {
+		flushableDoc.markFlushed(true)
+	}
+
+	return doc, nil
+}
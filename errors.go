@@ -0,0 +1,28 @@
+package mongoid
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrDuplicateKey is returned by persistence methods (Upsert, FindOrCreateBy, ...) when
+// the underlying driver reports a duplicate key error (MongoDB error code E11000), so
+// callers can distinguish it from other transport/driver errors via errors.Is(err,
+// ErrDuplicateKey) without inspecting the underlying mongo-driver error type themselves.
+var ErrDuplicateKey = errors.New("mongoid: duplicate key error")
+
+// wrapWriteError normalizes a mongo-driver write error by wrapping it in ErrDuplicateKey
+// when it is a duplicate key violation (so errors.Is still matches while the original
+// driver error -- message, offending key, server response -- is preserved), otherwise it
+// is returned unchanged.
+func wrapWriteError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %v", ErrDuplicateKey, err)
+	}
+	return err
+}
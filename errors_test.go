@@ -0,0 +1,39 @@
+package mongoid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestWrapWriteError(t *testing.T) {
+	t.Run("nil passes through unchanged", func(t *testing.T) {
+		if err := wrapWriteError(nil); err != nil {
+			t.Errorf("wrapWriteError(nil) = %v, want nil", err)
+		}
+	})
+
+	t.Run("a non-duplicate-key error is returned unchanged", func(t *testing.T) {
+		original := errors.New("boom")
+		if err := wrapWriteError(original); err != original {
+			t.Errorf("wrapWriteError(%v) = %v, want the same error back", original, err)
+		}
+	})
+
+	t.Run("a duplicate-key error wraps ErrDuplicateKey while preserving the original", func(t *testing.T) {
+		original := mongo.WriteException{
+			WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}},
+		}
+
+		wrapped := wrapWriteError(original)
+
+		if !errors.Is(wrapped, ErrDuplicateKey) {
+			t.Errorf("errors.Is(wrapWriteError(%v), ErrDuplicateKey) = false, want true", original)
+		}
+		if !strings.Contains(wrapped.Error(), "E11000") {
+			t.Errorf("wrapWriteError(%v) = %q, want it to retain the original driver message", original, wrapped.Error())
+		}
+	})
+}
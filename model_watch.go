@@ -0,0 +1,192 @@
+package mongoid
+
+/*
+This file implements Model.Watch(), a change-stream subscription on top of the go
+driver's mongo.Collection.Watch that dispatches decoded events to a typed handler,
+manages resume tokens, and auto-reconnects on transient errors.
+*/
+
+import (
+	"context"
+	"fmt"
+	"mongoid/log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WatchEventType mirrors the change stream "operationType" field.
+type WatchEventType string
+
+const (
+	WatchEventInsert     WatchEventType = "insert"
+	WatchEventUpdate     WatchEventType = "update"
+	WatchEventReplace    WatchEventType = "replace"
+	WatchEventDelete     WatchEventType = "delete"
+	WatchEventInvalidate WatchEventType = "invalidate"
+)
+
+// WatchEvent is the decoded form of a single change stream document.
+type WatchEvent struct {
+	OperationType WatchEventType
+	DocumentKey   BsonDocument
+	FullDocument  IDocumentBase // nil for delete/invalidate events
+	ResumeToken   bson.Raw
+}
+
+// WatchHandler is called once per change stream event observed by a Watcher.
+type WatchHandler func(event WatchEvent)
+
+// ResumeTokenStore lets a caller persist the last-seen resume token somewhere durable
+// (e.g. a database row), so a Watch() survives process restarts without replaying
+// already-seen events. When omitted, the resume token is only kept in-memory.
+type ResumeTokenStore interface {
+	SaveResumeToken(collectionName string, token bson.Raw) error
+	LoadResumeToken(collectionName string) (bson.Raw, error)
+}
+
+// WatchOption configures a Watcher at Watch()-time.
+type WatchOption func(*Watcher)
+
+// WithResumeTokenStore makes the Watcher load its starting resume token from store, and
+// persist each new token to store as events are observed.
+func WithResumeTokenStore(store ResumeTokenStore) WatchOption {
+	return func(w *Watcher) {
+		w.tokenStore = store
+	}
+}
+
+// Watcher is a running change-stream subscription returned by Model.Watch(). Call
+// Stop() to shut it down gracefully.
+type Watcher struct {
+	model       *Model
+	stream      *mongo.ChangeStream
+	cancel      context.CancelFunc
+	doneCh      chan struct{}
+	stopOnce    sync.Once
+	resumeToken bson.Raw
+	tokenStore  ResumeTokenStore
+}
+
+// Watch opens a change stream against the model's collection filtered/shaped by
+// pipeline (an aggregation pipeline, e.g. bson.A{bson.D{{"$match", ...}}}), and
+// dispatches each decoded event to handler on its own goroutine until Stop() is called
+// or ctx is cancelled. Transient stream errors trigger an automatic reconnect using the
+// last-seen resume token, so the caller does not observe a gap in events.
+func (m *Model) Watch(ctx context.Context, pipeline interface{}, handler WatchHandler, opts ...WatchOption) (*Watcher, error) {
+	log.Debug("Model.Watch()")
+
+	watchCtx, cancel := context.WithCancel(ctx)
+
+	w := &Watcher{model: m, cancel: cancel, doneCh: make(chan struct{})}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.tokenStore != nil {
+		if token, err := w.tokenStore.LoadResumeToken(m.GetCollectionName()); err == nil && token != nil {
+			w.resumeToken = token
+		}
+	}
+
+	if err := w.open(watchCtx, pipeline); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go w.run(watchCtx, pipeline, handler)
+
+	return w, nil
+}
+
+// Stop cancels the change stream and blocks until its goroutine has actually exited,
+// unblocking a Next() that is parked waiting for the next event. Safe to call more than
+// once, including concurrently.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(w.cancel)
+	<-w.doneCh
+}
+
+func (w *Watcher) open(ctx context.Context, pipeline interface{}) error {
+	client := w.model.GetClient()
+	collection := client.getMongoCollectionHandle(w.model.GetDatabaseName(), w.model.GetCollectionName())
+
+	csOpts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if w.resumeToken != nil {
+		csOpts.SetResumeAfter(w.resumeToken)
+	}
+
+	stream, err := collection.Watch(ctx, pipeline, csOpts)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	w.stream = stream
+	return nil
+}
+
+func (w *Watcher) run(ctx context.Context, pipeline interface{}, handler WatchHandler) {
+	defer close(w.doneCh)
+	defer w.stream.Close(context.Background())
+
+	for {
+		if !w.stream.Next(ctx) {
+			if err := w.stream.Err(); err != nil && ctx.Err() == nil {
+				log.Error(err)
+				w.stream.Close(context.Background())
+				if err := w.open(ctx, pipeline); err != nil {
+					log.Error(err)
+					return
+				}
+				continue
+			}
+			return
+		}
+
+		var rawEvent bson.M
+		if err := w.stream.Decode(&rawEvent); err != nil {
+			log.Error(err)
+			continue
+		}
+
+		w.resumeToken = w.stream.ResumeToken()
+		if w.tokenStore != nil {
+			if err := w.tokenStore.SaveResumeToken(w.model.GetCollectionName(), w.resumeToken); err != nil {
+				log.Error(err)
+			}
+		}
+
+		handler(w.decodeEvent(rawEvent))
+	}
+}
+
+func (w *Watcher) decodeEvent(rawEvent bson.M) WatchEvent {
+	event := WatchEvent{
+		OperationType: WatchEventType(fmt.Sprint(rawEvent["operationType"])),
+		ResumeToken:   w.resumeToken,
+	}
+
+	if documentKey, ok := rawEvent["documentKey"].(bson.M); ok {
+		event.DocumentKey = BsonDocument(documentKey)
+	}
+
+	if fullDocumentBson, ok := rawEvent["fullDocument"].(bson.M); ok {
+		bytes, err := bson.Marshal(fullDocumentBson)
+		if err != nil {
+			log.Error(err)
+			return event
+		}
+
+		doc := w.model.New()
+		if err := bson.Unmarshal(bytes, doc); err != nil {
+			log.Error(err)
+			return event
+		}
+		event.FullDocument = doc
+	}
+
+	return event
+}
@@ -0,0 +1,115 @@
+package mongoid
+
+/*
+This file adds transaction support on top of the go driver's mongo.Session, so that
+writes against several models can be committed atomically. It is a cross-cutting change
+exercised by Base's *Ctx methods (SaveCtx, DestroyCtx, UpsertCtx) and Reload, which look for
+a transactionState on the context to (a) join the session and (b) register themselves
+for rollback if the transaction's handler fails.
+*/
+
+import (
+	"context"
+	"mongoid/log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SessionContext is a context.Context that is also bound to an in-progress driver
+// session/transaction. Pass it to the *Ctx persistence methods (SaveCtx, DestroyCtx,
+// UpsertCtx, Reload) to have those writes join the transaction.
+type SessionContext = mongo.SessionContext
+
+// transactionEntry snapshots the in-memory change-tracking state a *Ctx method is about
+// to mutate, so it can be restored if the enclosing transaction is aborted.
+type transactionEntry struct {
+	doc               *Base
+	previousPersisted bool
+	previousValue     BsonDocument
+}
+
+// transactionState accumulates the documents mutated during a single WithTransaction
+// handler invocation, so they can all be rolled back together on abort.
+type transactionState struct {
+	mutex   sync.Mutex
+	entries []transactionEntry
+}
+
+// track records doc's pre-mutation state the first time it is seen during this
+// transaction attempt; later calls for the same doc within the same attempt are no-ops,
+// so rollback always restores the state doc had before the transaction began.
+func (s *transactionState) track(d *Base) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, entry := range s.entries {
+		if entry.doc == d {
+			return
+		}
+	}
+	s.entries = append(s.entries, transactionEntry{
+		doc:               d,
+		previousPersisted: d.persisted,
+		previousValue:     d.previousValue,
+	})
+}
+
+// rollback restores every tracked document to the state it had before the transaction
+// attempt began, so a retried handler closure sees a clean starting point.
+func (s *transactionState) rollback() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, entry := range s.entries {
+		entry.doc.persisted = entry.previousPersisted
+		entry.doc.previousValue = entry.previousValue
+	}
+}
+
+type transactionStateKeyType struct{}
+
+var transactionStateKey = transactionStateKeyType{}
+
+func withTransactionState(ctx context.Context, state *transactionState) context.Context {
+	return context.WithValue(ctx, transactionStateKey, state)
+}
+
+func transactionStateFromContext(ctx context.Context) *transactionState {
+	state, _ := ctx.Value(transactionStateKey).(*transactionState)
+	return state
+}
+
+// WithTransaction starts a driver session on the client and runs handler inside a
+// single MongoDB transaction, committing on success. If handler returns an error, or
+// the driver itself aborts the transaction (e.g. on a transient transaction error after
+// retries), the transaction is aborted and every document mutated via a *Ctx method
+// during the failed attempt has its IsPersisted()/Changes() state reverted, so a retry
+// of the same handler observes a clean starting state.
+func (c *Client) WithTransaction(ctx context.Context, handler func(sc SessionContext) error) error {
+	log.Debug("Client.WithTransaction()")
+
+	driverSession, err := c.getMongoClientHandle().StartSession()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer driverSession.EndSession(ctx)
+
+	var state *transactionState
+	_, err = driverSession.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if state != nil {
+			// the driver is retrying after a failed commit of the previous attempt
+			state.rollback()
+		}
+		state = &transactionState{}
+		handlerErr := handler(withTransactionState(sc, state))
+		if handlerErr != nil {
+			state.rollback()
+		}
+		return nil, handlerErr
+	})
+
+	if err != nil && state != nil {
+		state.rollback()
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package mongoid
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTransactionStateTrackAndRollback(t *testing.T) {
+	doc := &Base{persisted: true, previousValue: BsonDocument{"name": "Ada"}}
+
+	state := &transactionState{}
+	state.track(doc)
+
+	// mutate doc as SaveCtx/DestroyCtx would during the (about to fail) transaction
+	doc.persisted = false
+	doc.previousValue = BsonDocument{"name": "Grace"}
+
+	// tracking the same doc a second time in the same attempt must not overwrite the
+	// original pre-transaction snapshot
+	state.track(doc)
+
+	state.rollback()
+
+	if !doc.persisted {
+		t.Errorf("rollback() left persisted = false, want true (the pre-transaction value)")
+	}
+	if !reflect.DeepEqual(doc.previousValue, BsonDocument{"name": "Ada"}) {
+		t.Errorf("rollback() left previousValue = %v, want %v", doc.previousValue, BsonDocument{"name": "Ada"})
+	}
+}
+
+func TestTransactionStateContextRoundTrip(t *testing.T) {
+	if state := transactionStateFromContext(context.Background()); state != nil {
+		t.Fatalf("transactionStateFromContext(context.Background()) = %v, want nil", state)
+	}
+
+	state := &transactionState{}
+	ctx := withTransactionState(context.Background(), state)
+
+	if got := transactionStateFromContext(ctx); got != state {
+		t.Errorf("transactionStateFromContext() = %p, want %p", got, state)
+	}
+}
@@ -0,0 +1,254 @@
+package mongoid
+
+/*
+This file implements UnitOfWork, a batching layer that collects Save()/Destroy() intents
+against tracked documents without touching the database, then issues one unordered
+BulkWrite per collection on Flush(). This is a performance-motivated alternative to
+individual Save()/Destroy() calls for workloads that touch many documents per request.
+*/
+
+import (
+	"context"
+	"mongoid/log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type unitOfWorkOpKind int
+
+const (
+	unitOfWorkOpInsert unitOfWorkOpKind = iota
+	unitOfWorkOpUpdate
+	unitOfWorkOpDelete
+)
+
+type unitOfWorkEntry struct {
+	doc  IDocumentBase
+	kind unitOfWorkOpKind
+}
+
+// collectionKey identifies the (database, collection) a tracked entry's BulkWrite
+// should be grouped into.
+type collectionKey struct {
+	databaseName   string
+	collectionName string
+}
+
+// groupEntriesByKey buckets entries by the key keyFn derives for each, preserving each
+// bucket's entries in their original relative order and returning the keys themselves in
+// first-seen order, so Flush() can visit buckets deterministically. Split out from
+// Flush() so the grouping/ordering logic can be unit tested without a live collection.
+func groupEntriesByKey(entries []unitOfWorkEntry, keyFn func(unitOfWorkEntry) collectionKey) ([]collectionKey, map[collectionKey][]unitOfWorkEntry) {
+	order := []collectionKey{}
+	groups := map[collectionKey][]unitOfWorkEntry{}
+	for _, entry := range entries {
+		key := keyFn(entry)
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], entry)
+	}
+	return order, groups
+}
+
+// flushable is implemented automatically by any document embedding Base, letting
+// UnitOfWork update change-tracking state after a successful Flush() without needing
+// access to Base's unexported fields directly.
+type flushable interface {
+	markFlushed(persisted bool)
+}
+
+func (d *Base) markFlushed(persisted bool) {
+	d.persisted = persisted
+	d.refreshPreviousValueBSON() // update change tracking with current values
+}
+
+// UnitOfWork batches writes across documents -- possibly across several models/
+// collections -- so they can be sent to MongoDB as one BulkWrite per collection instead
+// of one round trip per document.
+type UnitOfWork struct {
+	mutex   sync.Mutex
+	entries []unitOfWorkEntry
+}
+
+// NewUnitOfWork returns an empty UnitOfWork ready to collect Save()/Destroy() calls.
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+// Save registers doc to be inserted (if new) or updated (if persisted) on the next
+// Flush(). It does not touch the database.
+func (u *UnitOfWork) Save(doc IDocumentBase) {
+	log.Debug("UnitOfWork.Save()")
+
+	kind := unitOfWorkOpUpdate
+	if !doc.IsPersisted() {
+		kind = unitOfWorkOpInsert
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.entries = append(u.entries, unitOfWorkEntry{doc: doc, kind: kind})
+}
+
+// Destroy registers doc to be deleted on the next Flush(). It does not touch the
+// database.
+func (u *UnitOfWork) Destroy(doc IDocumentBase) {
+	log.Debug("UnitOfWork.Destroy()")
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.entries = append(u.entries, unitOfWorkEntry{doc: doc, kind: unitOfWorkOpDelete})
+}
+
+// Flush groups all tracked Save()/Destroy() intents by collection and issues one
+// unordered BulkWrite per collection. On success, inserted/upserted _id values are
+// populated back onto their documents and each document's change-tracking snapshot is
+// refreshed, exactly as Save() would do for a single document.
+func (u *UnitOfWork) Flush(ctx context.Context) error {
+	log.Debug("UnitOfWork.Flush()")
+
+	u.mutex.Lock()
+	entries := u.entries
+	u.entries = nil
+	u.mutex.Unlock()
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	order, groups := groupEntriesByKey(entries, func(entry unitOfWorkEntry) collectionKey {
+		model := entry.doc.Model()
+		return collectionKey{databaseName: model.GetDatabaseName(), collectionName: model.GetCollectionName()}
+	})
+
+	for _, key := range order {
+		group := groups[key]
+		client := group[0].doc.Model().GetClient()
+		collection := client.getMongoCollectionHandle(key.databaseName, key.collectionName)
+		if err := flushGroup(ctx, collection, group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func flushGroup(ctx context.Context, collection *mongo.Collection, entries []unitOfWorkEntry) error {
+	writeModels := make([]mongo.WriteModel, 0, len(entries))
+	activeEntries := make([]unitOfWorkEntry, 0, len(entries))
+	// BulkWriteResult reports upserted _id values but not inserted ones, so a missing or
+	// zero _id is assigned here, keyed by write model index just like UpsertedIDs
+	insertedIDs := map[int64]interface{}{}
+
+	for _, entry := range entries {
+		switch entry.kind {
+		case unitOfWorkOpInsert:
+			touchTimestamps(entry.doc, true)
+			if err := runBeforeSaveCallbacksOn(entry.doc, true); err != nil {
+				return err
+			}
+
+			insertBson := entry.doc.ToBson()
+			idValue, found := insertBson["_id"]
+			if objectID, ok := idValue.(ObjectID); !found || (ok && objectID == ZeroObjectID()) {
+				idValue = primitive.NewObjectID()
+				insertBson["_id"] = idValue
+				insertedIDs[int64(len(writeModels))] = idValue
+			}
+			writeModels = append(writeModels, mongo.NewInsertOneModel().SetDocument(insertBson))
+			activeEntries = append(activeEntries, entry)
+
+		case unitOfWorkOpUpdate:
+			touchTimestamps(entry.doc, false)
+			if err := runBeforeSaveCallbacksOn(entry.doc, false); err != nil {
+				return err
+			}
+
+			// recompute the diff only after the before-hooks (and the Timestamps
+			// touch above) have run, since either may mutate fields that belong in
+			// this update
+			diffBson := entry.doc.Changes()
+			if len(diffBson) == 0 {
+				// nothing changed since the last load, nothing to write
+				continue
+			}
+
+			updateBson := buildSetUnsetUpdate(diffBson)
+			filterBson := BsonDocument{"_id": entry.doc.ToBson()["_id"]}
+			writeModels = append(writeModels, mongo.NewUpdateOneModel().SetFilter(filterBson).SetUpdate(updateBson))
+			activeEntries = append(activeEntries, entry)
+
+		case unitOfWorkOpDelete:
+			if hook, ok := entry.doc.(BeforeDestroy); ok {
+				if err := hook.BeforeDestroy(); err != nil {
+					return err
+				}
+			}
+
+			filterBson := BsonDocument{"_id": entry.doc.ToBson()["_id"]}
+			writeModels = append(writeModels, mongo.NewDeleteOneModel().SetFilter(filterBson))
+			activeEntries = append(activeEntries, entry)
+		}
+	}
+
+	if len(writeModels) == 0 {
+		return nil
+	}
+
+	result, err := collection.BulkWrite(ctx, writeModels, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return wrapWriteError(err)
+	}
+
+	return applyBulkWriteResults(activeEntries, insertedIDs, result.UpsertedIDs)
+}
+
+// applyBulkWriteResults runs the post-BulkWrite bookkeeping for each entry that made it
+// into the batch: populating any server-assigned _id, refreshing change-tracking state
+// via markFlushed, and dispatching After* callbacks. Split out from flushGroup so this
+// logic can be unit tested against a fake doc double without a live *mongo.Collection.
+func applyBulkWriteResults(activeEntries []unitOfWorkEntry, insertedIDs, upsertedIDs map[int64]interface{}) error {
+	for index, entry := range activeEntries {
+		if entry.kind == unitOfWorkOpDelete {
+			if flushableDoc, ok := entry.doc.(flushable); ok {
+				flushableDoc.markFlushed(false)
+			}
+			if hook, ok := entry.doc.(AfterDestroy); ok {
+				if err := hook.AfterDestroy(); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if entry.kind == unitOfWorkOpInsert {
+			if insertedID, ok := insertedIDs[int64(index)]; ok {
+				if err := entry.doc.SetField("_id", insertedID); err != nil {
+					log.Error(err)
+					return err
+				}
+			}
+		}
+
+		if upsertedID, ok := upsertedIDs[int64(index)]; ok {
+			if err := entry.doc.SetField("_id", upsertedID); err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+
+		if flushableDoc, ok := entry.doc.(flushable); ok {
+			flushableDoc.markFlushed(true)
+		}
+
+		if err := runAfterSaveCallbacksOn(entry.doc, entry.kind == unitOfWorkOpInsert); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package mongoid
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeFlushableDoc is a minimal IDocumentBase double that records the bookkeeping
+// applyBulkWriteResults performs on it, without needing a live *mongo.Collection or
+// *Model behind it.
+type fakeFlushableDoc struct {
+	fields       BsonDocument
+	flushedCalls int
+	flushedWith  bool
+	afterSaveErr error
+	afterSaveHit bool
+	afterDestroy bool
+}
+
+func newFakeFlushableDoc() *fakeFlushableDoc {
+	return &fakeFlushableDoc{fields: BsonDocument{}}
+}
+
+func (f *fakeFlushableDoc) IsPersisted() bool     { return false }
+func (f *fakeFlushableDoc) Model() *Model         { return nil }
+func (f *fakeFlushableDoc) ToBson() BsonDocument  { return f.fields }
+func (f *fakeFlushableDoc) Changes() BsonDocument { return BsonDocument{} }
+func (f *fakeFlushableDoc) SetField(key string, value interface{}) error {
+	f.fields[key] = value
+	return nil
+}
+func (f *fakeFlushableDoc) markFlushed(persisted bool) {
+	f.flushedCalls++
+	f.flushedWith = persisted
+}
+func (f *fakeFlushableDoc) AfterSave() error {
+	f.afterSaveHit = true
+	return f.afterSaveErr
+}
+func (f *fakeFlushableDoc) AfterDestroy() error {
+	f.afterDestroy = true
+	return nil
+}
+
+func TestGroupEntriesByKey(t *testing.T) {
+	keyA := collectionKey{databaseName: "db", collectionName: "a"}
+	keyB := collectionKey{databaseName: "db", collectionName: "b"}
+
+	entries := []unitOfWorkEntry{
+		{kind: unitOfWorkOpInsert}, // -> a
+		{kind: unitOfWorkOpUpdate}, // -> b
+		{kind: unitOfWorkOpDelete}, // -> a
+	}
+	keyFor := map[int]collectionKey{0: keyA, 1: keyB, 2: keyA}
+	keyFn := func(entry unitOfWorkEntry) collectionKey {
+		for index := range entries {
+			if entries[index] == entry {
+				return keyFor[index]
+			}
+		}
+		t.Fatalf("keyFn called with an entry not in the input slice: %v", entry)
+		return collectionKey{}
+	}
+
+	order, groups := groupEntriesByKey(entries, keyFn)
+
+	if !reflect.DeepEqual(order, []collectionKey{keyA, keyB}) {
+		t.Errorf("order = %v, want keys in first-seen order %v", order, []collectionKey{keyA, keyB})
+	}
+	if got := groups[keyA]; !reflect.DeepEqual(got, []unitOfWorkEntry{entries[0], entries[2]}) {
+		t.Errorf("groups[keyA] = %v, want entries 0 and 2 in original relative order", got)
+	}
+	if got := groups[keyB]; !reflect.DeepEqual(got, []unitOfWorkEntry{entries[1]}) {
+		t.Errorf("groups[keyB] = %v, want entry 1", got)
+	}
+}
+
+func TestGroupEntriesByKeyEmpty(t *testing.T) {
+	order, groups := groupEntriesByKey(nil, func(unitOfWorkEntry) collectionKey { return collectionKey{} })
+
+	if len(order) != 0 {
+		t.Errorf("order = %v, want empty", order)
+	}
+	if len(groups) != 0 {
+		t.Errorf("groups = %v, want empty", groups)
+	}
+}
+
+func TestApplyBulkWriteResultsInsertPopulatesIDAndMarksFlushed(t *testing.T) {
+	doc := newFakeFlushableDoc()
+	entries := []unitOfWorkEntry{{doc: doc, kind: unitOfWorkOpInsert}}
+
+	err := applyBulkWriteResults(entries, map[int64]interface{}{0: "new-id"}, nil)
+	if err != nil {
+		t.Fatalf("applyBulkWriteResults() = %v, want nil", err)
+	}
+	if doc.fields["_id"] != "new-id" {
+		t.Errorf("doc._id = %v, want %q", doc.fields["_id"], "new-id")
+	}
+	if doc.flushedCalls != 1 || !doc.flushedWith {
+		t.Errorf("markFlushed called %d time(s) with %v, want once with true", doc.flushedCalls, doc.flushedWith)
+	}
+	if !doc.afterSaveHit {
+		t.Error("AfterSave was not dispatched for an inserted entry")
+	}
+}
+
+func TestApplyBulkWriteResultsUpsertPopulatesID(t *testing.T) {
+	doc := newFakeFlushableDoc()
+	entries := []unitOfWorkEntry{{doc: doc, kind: unitOfWorkOpUpdate}}
+
+	err := applyBulkWriteResults(entries, nil, map[int64]interface{}{0: "upserted-id"})
+	if err != nil {
+		t.Fatalf("applyBulkWriteResults() = %v, want nil", err)
+	}
+	if doc.fields["_id"] != "upserted-id" {
+		t.Errorf("doc._id = %v, want %q", doc.fields["_id"], "upserted-id")
+	}
+	if doc.flushedCalls != 1 || !doc.flushedWith {
+		t.Errorf("markFlushed called %d time(s) with %v, want once with true", doc.flushedCalls, doc.flushedWith)
+	}
+}
+
+func TestApplyBulkWriteResultsDeleteMarksUnpersisted(t *testing.T) {
+	doc := newFakeFlushableDoc()
+	entries := []unitOfWorkEntry{{doc: doc, kind: unitOfWorkOpDelete}}
+
+	err := applyBulkWriteResults(entries, nil, nil)
+	if err != nil {
+		t.Fatalf("applyBulkWriteResults() = %v, want nil", err)
+	}
+	if doc.flushedCalls != 1 || doc.flushedWith {
+		t.Errorf("markFlushed called %d time(s) with %v, want once with false", doc.flushedCalls, doc.flushedWith)
+	}
+	if !doc.afterDestroy {
+		t.Error("AfterDestroy was not dispatched for a deleted entry")
+	}
+	if doc.afterSaveHit {
+		t.Error("AfterSave must not be dispatched for a deleted entry")
+	}
+}
+
+func TestApplyBulkWriteResultsStopsOnAfterSaveError(t *testing.T) {
+	failing := newFakeFlushableDoc()
+	failing.afterSaveErr = errors.New("after save boom")
+	next := newFakeFlushableDoc()
+
+	entries := []unitOfWorkEntry{
+		{doc: failing, kind: unitOfWorkOpInsert},
+		{doc: next, kind: unitOfWorkOpInsert},
+	}
+
+	err := applyBulkWriteResults(entries, nil, nil)
+	if err == nil {
+		t.Fatal("applyBulkWriteResults() = nil, want the AfterSave error")
+	}
+	if next.flushedCalls != 0 {
+		t.Error("applyBulkWriteResults kept processing entries after an AfterSave error")
+	}
+}